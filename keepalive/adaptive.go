@@ -0,0 +1,241 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"sync"
+	"time"
+)
+
+// Adaptive configures RTT-driven adjustment of the keepalive ping interval
+// and timeout. It is consulted by an AdaptiveController, which the
+// transport asks each time it schedules the next ping.
+//
+// Adaptive配置了基于RTT动态调整keepalive ping间隔和超时时间的参数，
+// 由AdaptiveController使用，transport每次安排下一次ping时都会向它询问。
+type Adaptive struct {
+	// Min is the smallest interval the controller will shrink Time to.
+	//
+	// Min是controller允许缩小到的最小ping间隔。
+	Min time.Duration
+	// Max is the largest interval the controller will back off Time to
+	// after missed acks.
+	//
+	// Max是丢包后controller允许退避到的最大ping间隔。
+	Max time.Duration
+	// TargetLossRatio is the highest ack-loss rate (timed-out pings over
+	// pings sent) at which an acked ping still shrinks the interval toward
+	// Min; above it, acks leave the interval unchanged. For example 0.1
+	// means "stop shrinking once more than roughly 1 in 10 pings has gone
+	// unacked". Every missed ack backs the interval off regardless.
+	//
+	// TargetLossRatio是允许ack继续将ping间隔向Min缩小的最大丢包率（超时ping数/已发送ping数），
+	// 超过它时ack不会改变间隔。例如0.1表示大约超过每10个ping中有1个未被确认时就停止缩小。
+	// 每次ping超时都会退避，与该值无关。
+	TargetLossRatio float64
+	// Alpha is the EWMA smoothing factor applied to each RTT sample,
+	// 0 < Alpha <= 1. A larger Alpha weights recent samples more heavily.
+	// If zero, DefaultAlpha is used.
+	//
+	// Alpha是应用于每个RTT采样的EWMA平滑系数，0 < Alpha <= 1，
+	// 越大则越偏向最近的采样。如果为零，则使用DefaultAlpha。
+	Alpha float64
+}
+
+// DefaultAlpha is the EWMA smoothing factor used when Adaptive.Alpha is
+// unset.
+const DefaultAlpha = 0.2
+
+// backoffFactor is the multiplier applied to the interval on a missed ack.
+const backoffFactor = 2
+
+// AdaptiveController tracks an EWMA of ping RTT and ack-loss rate for a
+// single transport and derives the next ping Time and Timeout from it. It
+// is safe for concurrent use.
+//
+// AdaptiveController为单个transport维护ping RTT和丢包率的EWMA，
+// 并据此推导下一次ping的Time和Timeout。可以并发使用。
+type AdaptiveController struct {
+	cfg Adaptive
+	// base is the configured Time/Timeout used as the starting point and,
+	// when cfg.Min/Max are zero, as the bounds.
+	base ClientParameters
+	// store, if non-nil, is consulted instead of base for the current
+	// Time/Timeout floor, so a ClientStore.Set call takes effect on this
+	// controller's very next NextInterval/NextTimeout without recreating
+	// it. Set by NewAdaptiveControllerFromStore.
+	store *ClientStore
+	// eh is notified of the same ping lifecycle this controller tracks; it
+	// is never nil (a nil base.EventHandler is normalized via nopIfNil).
+	eh EventHandler
+
+	mu       sync.Mutex
+	rtt      time.Duration
+	next     time.Duration // current Time estimate
+	baseTime time.Duration // currentBase().Time that next was last derived from
+	sent     int           // total pings dispatched, via OnPingSent
+	acked    int           // total pings acked, via OnPingAck
+	missed   int           // total pings that timed out, via OnPingTimeout
+}
+
+// NewAdaptiveController creates an AdaptiveController seeded from base's
+// Time and Timeout. base.Adaptive is used for the tunable bounds; if nil,
+// cfg's zero value (which falls back to base.Time/base.Timeout as bounds)
+// is used. Ping lifecycle events are forwarded to base.EventHandler (or
+// NopEventHandler, if nil).
+func NewAdaptiveController(base ClientParameters) *AdaptiveController {
+	cfg := Adaptive{}
+	if base.Adaptive != nil {
+		cfg = *base.Adaptive
+	}
+	if cfg.Alpha <= 0 {
+		cfg.Alpha = DefaultAlpha
+	}
+	if cfg.Min <= 0 {
+		cfg.Min = base.Time
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = base.Time
+	}
+	return &AdaptiveController{
+		cfg:  cfg,
+		base: base,
+		eh:   nopIfNil(base.EventHandler),
+	}
+}
+
+// NewAdaptiveControllerFromStore creates an AdaptiveController seeded from
+// s's current ClientParameters, and keeps reading the Time/Timeout floor
+// from s on every NextInterval/NextTimeout call, so a later s.Set takes
+// effect without the transport recreating its controller.
+func NewAdaptiveControllerFromStore(s *ClientStore) *AdaptiveController {
+	c := NewAdaptiveController(s.Get())
+	c.store = s
+	return c
+}
+
+// currentBase returns the base used for the Time/Timeout floor: s.Get(),
+// if this controller was created via NewAdaptiveControllerFromStore, or
+// the fixed base it was otherwise constructed with.
+func (c *AdaptiveController) currentBase() ClientParameters {
+	if c.store != nil {
+		return c.store.Get()
+	}
+	return c.base
+}
+
+// ensureNextLocked returns c.next, re-deriving it from the live
+// currentBase().Time first if this is the first call (a controller that
+// hasn't seen any ping feedback yet starts its backoff/shrink math from the
+// current configuration instead of a value frozen at construction) or if
+// currentBase().Time has changed since next was last derived from it (a
+// store-backed Set takes effect immediately instead of being masked by
+// already-accumulated backoff/shrink state). Callers must hold c.mu.
+func (c *AdaptiveController) ensureNextLocked() time.Duration {
+	if base := c.currentBase().Time; c.next <= 0 || base != c.baseTime {
+		c.next = base
+		c.baseTime = base
+	}
+	return c.next
+}
+
+// OnPingSent records that a ping was just dispatched. The transport must
+// call this once per ping, before the eventual OnPingAck or OnPingTimeout
+// for that same ping, so the ack-loss ratio can be computed correctly.
+func (c *AdaptiveController) OnPingSent() {
+	c.mu.Lock()
+	c.sent++
+	c.mu.Unlock()
+	c.eh.OnPingSent()
+}
+
+// OnPingAck records a successful ping round-trip of the given duration and
+// updates the RTT estimate and next interval accordingly.
+func (c *AdaptiveController) OnPingAck(rtt time.Duration) {
+	c.mu.Lock()
+	if c.rtt == 0 {
+		c.rtt = rtt
+	} else {
+		c.rtt = time.Duration(c.cfg.Alpha*float64(rtt) + (1-c.cfg.Alpha)*float64(c.rtt))
+	}
+	c.acked++
+
+	// Shrink toward Min as long as the loss ratio stays at or under target.
+	c.ensureNextLocked()
+	if c.lossRatioLocked() <= c.cfg.TargetLossRatio {
+		c.next = maxDuration(c.cfg.Min, c.next/2+c.cfg.Min/2)
+	}
+	c.mu.Unlock()
+	c.eh.OnPingAck(rtt)
+}
+
+// OnPingTimeout records a ping that went unacked and backs the interval off
+// toward Max.
+func (c *AdaptiveController) OnPingTimeout() {
+	c.mu.Lock()
+	c.missed++
+	c.ensureNextLocked()
+	c.next = minDuration(c.cfg.Max, c.next*backoffFactor)
+	c.mu.Unlock()
+	c.eh.OnPingTimeout()
+}
+
+// NextInterval returns the Time to wait before sending the next ping.
+func (c *AdaptiveController) NextInterval() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ensureNextLocked()
+}
+
+// NextTimeout returns the Timeout to apply to the next ping, derived as
+// max(configured Timeout, k*rtt) so that short-lived congestion doesn't
+// tear down the connection.
+func (c *AdaptiveController) NextTimeout() time.Duration {
+	const k = 4
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timeout := c.currentBase().Timeout
+	if c.rtt == 0 {
+		return timeout
+	}
+	return maxDuration(timeout, k*c.rtt)
+}
+
+// lossRatioLocked returns the fraction of dispatched pings that have timed
+// out so far. Callers must hold c.mu.
+func (c *AdaptiveController) lossRatioLocked() float64 {
+	if c.sent == 0 {
+		return 0
+	}
+	return float64(c.missed) / float64(c.sent)
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,125 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveControllerBacksOffOnTimeout(t *testing.T) {
+	c := NewAdaptiveController(ClientParameters{
+		Time:    10 * time.Second,
+		Timeout: 20 * time.Second,
+		Adaptive: &Adaptive{
+			Min: 10 * time.Second,
+			Max: 2 * time.Minute,
+		},
+	})
+
+	for i := 0; i < 10; i++ {
+		c.OnPingSent()
+		c.OnPingTimeout()
+	}
+	if got, want := c.NextInterval(), 2*time.Minute; got != want {
+		t.Fatalf("after 10 timeouts, NextInterval() = %v, want %v (capped at Max)", got, want)
+	}
+}
+
+// TestAdaptiveControllerHoldsOffShrinkUnderLoss reproduces the scenario
+// where a controller configured with zero tolerance for loss must not
+// shrink its interval immediately after a run of timeouts, even though the
+// very next ping is acked.
+func TestAdaptiveControllerHoldsOffShrinkUnderLoss(t *testing.T) {
+	c := NewAdaptiveController(ClientParameters{
+		Time:    10 * time.Second,
+		Timeout: 20 * time.Second,
+		Adaptive: &Adaptive{
+			Min:             10 * time.Second,
+			Max:             2 * time.Minute,
+			TargetLossRatio: 0,
+		},
+	})
+
+	for i := 0; i < 10; i++ {
+		c.OnPingSent()
+		c.OnPingTimeout()
+	}
+	backedOff := c.NextInterval()
+	if backedOff != 2*time.Minute {
+		t.Fatalf("NextInterval() after 10 timeouts = %v, want 2m0s", backedOff)
+	}
+
+	c.OnPingSent()
+	c.OnPingAck(5 * time.Millisecond)
+	if got := c.NextInterval(); got != backedOff {
+		t.Fatalf("NextInterval() after a single ack following 10 timeouts = %v, want unchanged %v (loss ratio still exceeds TargetLossRatio)", got, backedOff)
+	}
+}
+
+// TestAdaptiveControllerShrinksOnceLossRatioRecovers checks that, once
+// enough acked pings dilute the historical loss ratio back under target,
+// the controller resumes shrinking toward Min.
+func TestAdaptiveControllerShrinksOnceLossRatioRecovers(t *testing.T) {
+	c := NewAdaptiveController(ClientParameters{
+		Time:    10 * time.Second,
+		Timeout: 20 * time.Second,
+		Adaptive: &Adaptive{
+			Min:             10 * time.Second,
+			Max:             2 * time.Minute,
+			TargetLossRatio: 0.5,
+		},
+	})
+
+	c.OnPingSent()
+	c.OnPingTimeout()
+
+	// Each ack with the loss ratio at or under target halves the distance to
+	// Min; enough iterations converge exactly to Min.
+	for i := 0; i < 60; i++ {
+		c.OnPingSent()
+		c.OnPingAck(5 * time.Millisecond)
+	}
+	if got, want := c.NextInterval(), 10*time.Second; got != want {
+		t.Fatalf("NextInterval() after loss ratio recovered = %v, want %v (shrunk to Min)", got, want)
+	}
+}
+
+func TestAdaptiveControllerNextTimeoutTracksRTT(t *testing.T) {
+	c := NewAdaptiveController(ClientParameters{
+		Time:    10 * time.Second,
+		Timeout: 1 * time.Second,
+	})
+
+	c.OnPingSent()
+	c.OnPingAck(500 * time.Millisecond)
+	if got, want := c.NextTimeout(), 2*time.Second; got != want {
+		t.Fatalf("NextTimeout() = %v, want %v (k*rtt dominates configured Timeout)", got, want)
+	}
+
+	// Feed enough low-RTT samples for the EWMA to decay well under the
+	// configured Timeout floor.
+	for i := 0; i < 20; i++ {
+		c.OnPingSent()
+		c.OnPingAck(10 * time.Millisecond)
+	}
+	if got, want := c.NextTimeout(), 1*time.Second; got != want {
+		t.Fatalf("NextTimeout() = %v, want %v (configured Timeout floor, rtt decayed)", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import "time"
+
+// EventHandler receives notifications about keepalive activity on a single
+// transport. Implementations must not block, as they are invoked from the
+// transport's keepalive goroutine. A nil EventHandler field on
+// ClientParameters or ServerParameters is treated as NopEventHandler by
+// consumers such as AdaptiveController, via nopIfNil.
+//
+// EventHandler接收单个transport上keepalive活动的通知。实现方不能阻塞，
+// 因为它们是在transport的keepalive goroutine中被调用的。
+//
+// A stats/keepalive adapter to the existing stats.Handler pipeline is not
+// provided here: this tree has no stats package for it to adapt to.
+//
+// 这里没有提供将事件适配到现有stats.Handler管道的stats/keepalive适配器：
+// 本代码树中并不存在stats包可供适配。
+type EventHandler interface {
+	// OnPingSent is called right before a keepalive ping is written to the
+	// wire.
+	OnPingSent()
+	// OnPingAck is called when the peer acks a keepalive ping, with the
+	// measured round-trip time.
+	OnPingAck(rtt time.Duration)
+	// OnPingTimeout is called when a keepalive ping goes unacked for
+	// Timeout.
+	OnPingTimeout()
+	// OnGoAwaySent is called when this side sends a GOAWAY as a result of
+	// keepalive enforcement (e.g. MaxConnectionAge, ping strikes).
+	OnGoAwaySent(reason string)
+	// OnGoAwayReceived is called when a GOAWAY is received from the peer.
+	OnGoAwayReceived(reason string)
+	// OnConnectionClosedByKeepalive is called when the transport tears down
+	// the connection because a keepalive ping timed out.
+	OnConnectionClosedByKeepalive()
+}
+
+// NopEventHandler is an EventHandler whose methods do nothing. It is the
+// default used when a ClientParameters or ServerParameters leaves
+// EventHandler nil.
+type NopEventHandler struct{}
+
+// OnPingSent implements EventHandler.
+func (NopEventHandler) OnPingSent() {}
+
+// OnPingAck implements EventHandler.
+func (NopEventHandler) OnPingAck(time.Duration) {}
+
+// OnPingTimeout implements EventHandler.
+func (NopEventHandler) OnPingTimeout() {}
+
+// OnGoAwaySent implements EventHandler.
+func (NopEventHandler) OnGoAwaySent(string) {}
+
+// OnGoAwayReceived implements EventHandler.
+func (NopEventHandler) OnGoAwayReceived(string) {}
+
+// OnConnectionClosedByKeepalive implements EventHandler.
+func (NopEventHandler) OnConnectionClosedByKeepalive() {}
+
+// nopIfNil returns h, or NopEventHandler{} if h is nil, so callers never
+// need to nil-check before invoking callbacks.
+func nopIfNil(h EventHandler) EventHandler {
+	if h == nil {
+		return NopEventHandler{}
+	}
+	return h
+}
@@ -0,0 +1,79 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (r *recordingHandler) OnPingSent()              { r.events = append(r.events, "sent") }
+func (r *recordingHandler) OnPingAck(time.Duration)   { r.events = append(r.events, "ack") }
+func (r *recordingHandler) OnPingTimeout()            { r.events = append(r.events, "timeout") }
+func (r *recordingHandler) OnGoAwaySent(string)       {}
+func (r *recordingHandler) OnGoAwayReceived(string)   {}
+func (r *recordingHandler) OnConnectionClosedByKeepalive() {}
+
+func TestNopIfNilDefaultsToNopEventHandler(t *testing.T) {
+	h := nopIfNil(nil)
+	if _, ok := h.(NopEventHandler); !ok {
+		t.Fatalf("nopIfNil(nil) = %T, want NopEventHandler", h)
+	}
+
+	rec := &recordingHandler{}
+	if got := nopIfNil(rec); got != EventHandler(rec) {
+		t.Fatalf("nopIfNil(rec) = %v, want rec unchanged", got)
+	}
+}
+
+func TestAdaptiveControllerForwardsEventsToHandler(t *testing.T) {
+	rec := &recordingHandler{}
+	c := NewAdaptiveController(ClientParameters{
+		Time:         10 * time.Second,
+		Timeout:      20 * time.Second,
+		EventHandler: rec,
+	})
+
+	c.OnPingSent()
+	c.OnPingAck(5 * time.Millisecond)
+	c.OnPingSent()
+	c.OnPingTimeout()
+
+	want := []string{"sent", "ack", "sent", "timeout"}
+	if len(rec.events) != len(want) {
+		t.Fatalf("events = %v, want %v", rec.events, want)
+	}
+	for i, e := range want {
+		if rec.events[i] != e {
+			t.Fatalf("events = %v, want %v", rec.events, want)
+		}
+	}
+}
+
+func TestAdaptiveControllerDefaultsToNopEventHandler(t *testing.T) {
+	c := NewAdaptiveController(ClientParameters{Time: 10 * time.Second, Timeout: 20 * time.Second})
+	// Must not panic in the absence of an EventHandler.
+	c.OnPingSent()
+	c.OnPingAck(time.Millisecond)
+	c.OnPingTimeout()
+}
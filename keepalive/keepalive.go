@@ -55,6 +55,21 @@ type ClientParameters struct {
 	// 如果设为true：没有活跃的stream，也会走发送ping的逻辑
 	// 如果设为false：没有活跃的stream，就会阻塞在那里，只有有新的stream被创建出来，才会继续走发送ping的逻辑
 	PermitWithoutStream bool // false by default.
+	// Adaptive, if non-nil, lets the transport adjust Time and Timeout at
+	// runtime based on observed ping RTT and ack-loss rate instead of using
+	// fixed values. Time and Timeout above are still used as the starting
+	// point and as the floor/ceiling bounds when Adaptive's own Min/Max are
+	// zero.
+	//
+	// 如果设置了Adaptive，transport会根据观测到的ping RTT和丢包率动态调整Time和Timeout，
+	// 不再使用固定值。上面的Time和Timeout仍然作为起始值，以及Adaptive的Min/Max为零时的兜底边界。
+	Adaptive *Adaptive
+	// EventHandler, if non-nil, is notified of keepalive activity on every
+	// transport created by this ClientConn. If nil, events are dropped.
+	//
+	// 如果设置了EventHandler，该ClientConn创建的每个transport上的keepalive活动都会通知它。
+	// 如果为nil，事件会被丢弃。
+	EventHandler EventHandler
 }
 
 // ServerParameters is used to set keepalive and max-age parameters on the
@@ -91,6 +106,19 @@ type ServerParameters struct {
 	//
 	// 在发送了ping之后，server等待Timeout的时间后，连接还是没有活跃，就会关闭连接
 	Timeout time.Duration // The current default value is 20 seconds.
+	// Adaptive, if non-nil, lets the transport adjust Time and Timeout at
+	// runtime based on observed ping RTT and ack-loss rate instead of using
+	// fixed values, subject to the server's EnforcementPolicy.MinTime.
+	//
+	// 如果设置了Adaptive，transport会根据观测到的ping RTT和丢包率动态调整Time和Timeout，
+	// 同时仍然受限于server侧EnforcementPolicy.MinTime的约束。
+	Adaptive *Adaptive
+	// EventHandler, if non-nil, is notified of keepalive activity on every
+	// transport accepted by this Server. If nil, events are dropped.
+	//
+	// 如果设置了EventHandler，该Server接受的每个transport上的keepalive活动都会通知它。
+	// 如果为nil，事件会被丢弃。
+	EventHandler EventHandler
 }
 
 // EnforcementPolicy is used to set keepalive enforcement policy on the
@@ -108,4 +136,47 @@ type EnforcementPolicy struct {
 	//
 	// true: 没有活跃的流（active streams），也接受pings。不然将会发送GoAway给client，踢掉连接
 	PermitWithoutStream bool // false by default.
+	// PerMethod, if non-nil, overrides the above MinTime/PermitWithoutStream
+	// for connections that have an active stream whose full method name is
+	// a key of the map. This lets a server hosting both short unary RPCs
+	// and long-lived streaming RPCs enforce a looser policy for the
+	// latter. When a connection has streams open for more than one listed
+	// method, the most permissive matching policy applies; MethodPolicy
+	// selects it.
+	//
+	// 如果设置了PerMethod，对于连接上存在method名在该map中的活跃stream时，
+	// 会用对应的policy覆盖上面的MinTime/PermitWithoutStream，
+	// 使得同时服务短时unary RPC和长连接streaming RPC的server可以对后者放宽限制。
+	// 如果连接上同时有多个被列出的method的stream处于活跃状态，取其中最宽松的一个，
+	// 由MethodPolicy方法负责选择。
+	PerMethod map[string]EnforcementPolicy
+}
+
+// MethodPolicy returns the effective EnforcementPolicy for a connection
+// given the full method names of its currently active streams. If none of
+// the methods are present in p.PerMethod, p itself (with PerMethod
+// stripped) is returned. If more than one matches, the most permissive
+// policy is returned: PermitWithoutStream is true if any match sets it,
+// and MinTime is the smallest MinTime among the matches.
+func (p EnforcementPolicy) MethodPolicy(activeMethods []string) EnforcementPolicy {
+	effective := EnforcementPolicy{MinTime: p.MinTime, PermitWithoutStream: p.PermitWithoutStream}
+	matched := false
+	for _, m := range activeMethods {
+		mp, ok := p.PerMethod[m]
+		if !ok {
+			continue
+		}
+		if !matched {
+			effective = EnforcementPolicy{MinTime: mp.MinTime, PermitWithoutStream: mp.PermitWithoutStream}
+			matched = true
+			continue
+		}
+		if mp.PermitWithoutStream {
+			effective.PermitWithoutStream = true
+		}
+		if mp.MinTime < effective.MinTime {
+			effective.MinTime = mp.MinTime
+		}
+	}
+	return effective
 }
@@ -0,0 +1,97 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import "sync"
+
+// MethodTracker accounts for which RPC methods currently have an active
+// stream on a single connection, and resolves the effective
+// EnforcementPolicy for ping-strike decisions from that set via
+// EnforcementPolicy.MethodPolicy (or EnforcementFor, if backed by a
+// ServerStore). It replaces a single connection-wide strike counter keyed
+// only on a static policy with one that reacts to what's actually open, so
+// a connection with both short unary RPCs and a long-lived streaming RPC
+// is held to the more permissive of the matching policies.
+//
+// A MethodTracker is safe for concurrent use; server transports call
+// StreamOpened/StreamClosed as streams come and go, and Policy before
+// deciding whether an incoming ping is a strike.
+//
+// MethodTracker记录单个连接上当前有活跃stream的RPC method，并通过
+// EnforcementPolicy.MethodPolicy（或EnforcementFor，如果底层是ServerStore）
+// 根据这个集合解析出ping-strike判定应使用的EnforcementPolicy。
+// 它取代了只基于单一静态policy的连接级strike计数器，
+// 使得同时存在短时unary RPC和长连接streaming RPC的连接
+// 可以采用两者中更宽松的policy。
+type MethodTracker struct {
+	mu     sync.Mutex
+	active map[string]int // method -> number of open streams for it
+
+	// Exactly one of policy, store is used by Policy.
+	policy EnforcementPolicy
+	store  *ServerStore
+}
+
+// NewMethodTracker creates a MethodTracker that resolves policy against a
+// fixed EnforcementPolicy.
+func NewMethodTracker(policy EnforcementPolicy) *MethodTracker {
+	return &MethodTracker{active: make(map[string]int), policy: policy}
+}
+
+// NewMethodTrackerFromStore creates a MethodTracker that resolves policy
+// against s's current EnforcementPolicy, so a later s.Set takes effect on
+// this connection without it being re-established.
+func NewMethodTrackerFromStore(s *ServerStore) *MethodTracker {
+	return &MethodTracker{active: make(map[string]int), store: s}
+}
+
+// StreamOpened records that a stream for the given full method name was
+// opened on this connection.
+func (t *MethodTracker) StreamOpened(method string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[method]++
+}
+
+// StreamClosed records that a stream for the given full method name was
+// closed on this connection.
+func (t *MethodTracker) StreamClosed(method string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[method]--
+	if t.active[method] <= 0 {
+		delete(t.active, method)
+	}
+}
+
+// Policy returns the EnforcementPolicy to apply given the methods
+// currently active on this connection, per EnforcementPolicy.MethodPolicy.
+func (t *MethodTracker) Policy() EnforcementPolicy {
+	t.mu.Lock()
+	methods := make([]string, 0, len(t.active))
+	for m := range t.active {
+		methods = append(methods, m)
+	}
+	t.mu.Unlock()
+
+	if t.store != nil {
+		return t.store.EnforcementFor(methods)
+	}
+	return t.policy.MethodPolicy(methods)
+}
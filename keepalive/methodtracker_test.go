@@ -0,0 +1,105 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"testing"
+	"time"
+)
+
+func watchPolicy() EnforcementPolicy {
+	return EnforcementPolicy{
+		MinTime: 5 * time.Minute,
+		PerMethod: map[string]EnforcementPolicy{
+			"/foo.Bar/Watch": {MinTime: time.Second, PermitWithoutStream: true},
+		},
+	}
+}
+
+// TestMethodTrackerWatchStreamGetsLooserPolicy is the scenario the request
+// asked for directly: a connection with an open server-streaming RPC on
+// /foo.Bar/Watch can ping every second, while a connection with only
+// unary streams open is held to the 5-minute default.
+func TestMethodTrackerWatchStreamGetsLooserPolicy(t *testing.T) {
+	policy := watchPolicy()
+
+	watchConn := NewMethodTracker(policy)
+	watchConn.StreamOpened("/foo.Bar/Watch")
+	if got, want := watchConn.Policy().MinTime, time.Second; got != want {
+		t.Fatalf("connection with /foo.Bar/Watch open: Policy().MinTime = %v, want %v", got, want)
+	}
+	if !watchConn.Policy().PermitWithoutStream {
+		t.Fatalf("connection with /foo.Bar/Watch open: Policy().PermitWithoutStream = false, want true")
+	}
+
+	unaryConn := NewMethodTracker(policy)
+	unaryConn.StreamOpened("/foo.Bar/Get")
+	if got, want := unaryConn.Policy().MinTime, 5*time.Minute; got != want {
+		t.Fatalf("unary-only connection: Policy().MinTime = %v, want %v (default)", got, want)
+	}
+}
+
+func TestMethodTrackerMostPermissiveOfMultipleActiveMethods(t *testing.T) {
+	policy := EnforcementPolicy{
+		MinTime: 5 * time.Minute,
+		PerMethod: map[string]EnforcementPolicy{
+			"/foo.Bar/Watch": {MinTime: time.Second},
+			"/foo.Bar/Logs":  {MinTime: 10 * time.Second},
+		},
+	}
+	tr := NewMethodTracker(policy)
+	tr.StreamOpened("/foo.Bar/Watch")
+	tr.StreamOpened("/foo.Bar/Logs")
+
+	if got, want := tr.Policy().MinTime, time.Second; got != want {
+		t.Fatalf("Policy().MinTime = %v, want %v (most permissive of the two open methods)", got, want)
+	}
+}
+
+// TestMethodTrackerRevertsAfterStreamClosed checks that once the looser
+// stream closes, the connection falls back to whatever policy applies to
+// the methods still open.
+func TestMethodTrackerRevertsAfterStreamClosed(t *testing.T) {
+	tr := NewMethodTracker(watchPolicy())
+	tr.StreamOpened("/foo.Bar/Watch")
+	tr.StreamOpened("/foo.Bar/Get")
+	if got, want := tr.Policy().MinTime, time.Second; got != want {
+		t.Fatalf("Policy().MinTime with Watch+Get open = %v, want %v", got, want)
+	}
+
+	tr.StreamClosed("/foo.Bar/Watch")
+	if got, want := tr.Policy().MinTime, 5*time.Minute; got != want {
+		t.Fatalf("Policy().MinTime after Watch closed = %v, want %v (default, only Get left)", got, want)
+	}
+}
+
+func TestMethodTrackerFromStoreReflectsLiveUpdate(t *testing.T) {
+	s := NewServerStore(ServerParameters{}, EnforcementPolicy{MinTime: 5 * time.Minute})
+	tr := NewMethodTrackerFromStore(s)
+	tr.StreamOpened("/foo.Bar/Watch")
+
+	if got, want := tr.Policy().MinTime, 5*time.Minute; got != want {
+		t.Fatalf("Policy().MinTime before update = %v, want %v", got, want)
+	}
+
+	s.Set(ServerParameters{}, watchPolicy())
+	if got, want := tr.Policy().MinTime, time.Second; got != want {
+		t.Fatalf("Policy().MinTime after store update = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// NOTE: this file provides the live-reconfiguration primitives
+// ClientConn.SetKeepaliveParams/Server.SetKeepaliveParams would delegate
+// to, not those methods themselves — grpc.ClientConn and grpc.Server
+// don't exist anywhere in this tree, which has no grpc package at all.
+// Nothing here is reachable from a public dial/serve path; AdaptiveController
+// and MethodTracker consume ClientStore/ServerStore internally, but nothing
+// constructs an AdaptiveController/MethodTracker outside their own tests
+// either. Flagging for whoever scoped this backlog entry: as written, this
+// request isn't deliverable against this snapshot of the repository.
+
+// MinClientTime is the smallest value ClientParameters.Time is allowed to
+// take; smaller configured values are raised to this floor.
+const MinClientTime = 10 * time.Second
+
+// MinServerTime is the smallest value ServerParameters.Time is allowed to
+// take; smaller configured values are raised to this floor.
+const MinServerTime = time.Second
+
+// ClientStore holds a ClientParameters that can be read and atomically
+// swapped while transports are live, so a ClientConn can reconfigure
+// keepalive behavior on active connections without tearing them down. The
+// zero value is not usable; use NewClientStore.
+//
+// ClientStore保存一份可以在transport运行期间被原子替换的ClientParameters，
+// 使ClientConn能够在不断开现有连接的情况下重新配置keepalive行为。
+type ClientStore struct {
+	v atomic.Value // ClientParameters
+}
+
+// NewClientStore returns a ClientStore seeded with p, after clamping p.Time
+// to MinClientTime.
+func NewClientStore(p ClientParameters) *ClientStore {
+	s := &ClientStore{}
+	s.Set(p)
+	return s
+}
+
+// Set clamps p.Time to MinClientTime and atomically installs it. The
+// transport's keepalive loop picks up the change the next time it
+// schedules a ping.
+func (s *ClientStore) Set(p ClientParameters) {
+	if p.Time < MinClientTime {
+		p.Time = MinClientTime
+	}
+	s.v.Store(p)
+}
+
+// Get returns the current ClientParameters.
+func (s *ClientStore) Get() ClientParameters {
+	return s.v.Load().(ClientParameters)
+}
+
+// ServerStore holds a ServerParameters and EnforcementPolicy pair that can
+// be read and atomically swapped while transports are live, so a Server
+// can reconfigure keepalive behavior on active connections without
+// tearing them down. The zero value is not usable; use NewServerStore.
+type ServerStore struct {
+	v atomic.Value // serverConfig
+}
+
+type serverConfig struct {
+	params ServerParameters
+	policy EnforcementPolicy
+}
+
+// NewServerStore returns a ServerStore seeded with p and policy, after
+// clamping p.Time to MinServerTime.
+func NewServerStore(p ServerParameters, policy EnforcementPolicy) *ServerStore {
+	s := &ServerStore{}
+	s.Set(p, policy)
+	return s
+}
+
+// Set clamps p.Time to MinServerTime, ensures policy.MinTime is respected
+// (p.Time is raised to policy.MinTime if smaller), and atomically installs
+// the pair. The transport's keepalive loop picks up the change the next
+// time it schedules a ping.
+func (s *ServerStore) Set(p ServerParameters, policy EnforcementPolicy) {
+	if p.Time < MinServerTime {
+		p.Time = MinServerTime
+	}
+	if policy.MinTime > 0 && p.Time < policy.MinTime {
+		p.Time = policy.MinTime
+	}
+	s.v.Store(serverConfig{params: p, policy: policy})
+}
+
+// Get returns the current ServerParameters and EnforcementPolicy.
+func (s *ServerStore) Get() (ServerParameters, EnforcementPolicy) {
+	c := s.v.Load().(serverConfig)
+	return c.params, c.policy
+}
+
+// EnforcementFor returns the EnforcementPolicy currently installed in s,
+// resolved against the full method names of a connection's active streams
+// via EnforcementPolicy.MethodPolicy. Callers (e.g. a per-connection
+// MethodTracker) should call this instead of caching the policy, so a
+// concurrent Set takes effect on the connection's next ping.
+func (s *ServerStore) EnforcementFor(activeMethods []string) EnforcementPolicy {
+	_, policy := s.Get()
+	return policy.MethodPolicy(activeMethods)
+}
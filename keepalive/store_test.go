@@ -0,0 +1,111 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientStoreSetClampsToMinClientTime(t *testing.T) {
+	s := NewClientStore(ClientParameters{Time: time.Second})
+	if got := s.Get().Time; got != MinClientTime {
+		t.Fatalf("Get().Time = %v, want %v", got, MinClientTime)
+	}
+}
+
+// TestAdaptiveControllerFromStorePicksUpLiveUpdate simulates "flipping
+// keepalive parameters mid-connection": a ClientConn.SetKeepaliveParams
+// call would update the ClientStore shared with the connection's
+// transports; the transport's controller must reflect the new Time on its
+// very next call, without being recreated.
+func TestAdaptiveControllerFromStorePicksUpLiveUpdate(t *testing.T) {
+	s := NewClientStore(ClientParameters{Time: 30 * time.Second, Timeout: 20 * time.Second})
+	c := NewAdaptiveControllerFromStore(s)
+
+	if got, want := c.NextInterval(), 30*time.Second; got != want {
+		t.Fatalf("NextInterval() before update = %v, want %v", got, want)
+	}
+
+	s.Set(ClientParameters{Time: 15 * time.Second, Timeout: 5 * time.Second})
+
+	if got, want := c.NextInterval(), 15*time.Second; got != want {
+		t.Fatalf("NextInterval() after live update = %v, want %v", got, want)
+	}
+	if got, want := c.NextTimeout(), 5*time.Second; got != want {
+		t.Fatalf("NextTimeout() after live update = %v, want %v", got, want)
+	}
+}
+
+// TestAdaptiveControllerFromStorePicksUpLiveUpdateAfterPings checks the
+// same live-reconfiguration scenario once the controller has already
+// processed ping feedback, so next is no longer the construction-time
+// zero value ensureNextLocked seeds from.
+func TestAdaptiveControllerFromStorePicksUpLiveUpdateAfterPings(t *testing.T) {
+	s := NewClientStore(ClientParameters{Time: 30 * time.Second, Timeout: 20 * time.Second})
+	c := NewAdaptiveControllerFromStore(s)
+
+	c.OnPingSent()
+	c.OnPingAck(5 * time.Millisecond)
+	if got, want := c.NextInterval(), 30*time.Second; got != want {
+		t.Fatalf("NextInterval() after a single ack = %v, want %v (shrink is bounded by Min, which defaulted to Time)", got, want)
+	}
+
+	s.Set(ClientParameters{Time: 15 * time.Second, Timeout: 5 * time.Second})
+
+	if got, want := c.NextInterval(), 15*time.Second; got != want {
+		t.Fatalf("NextInterval() after live update following a ping = %v, want %v", got, want)
+	}
+}
+
+func TestServerStoreSetRespectsPolicyMinTime(t *testing.T) {
+	s := NewServerStore(ServerParameters{Time: 2 * time.Hour}, EnforcementPolicy{MinTime: 5 * time.Minute})
+	s.Set(ServerParameters{Time: time.Minute}, EnforcementPolicy{MinTime: 5 * time.Minute})
+
+	p, _ := s.Get()
+	if got, want := p.Time, 5*time.Minute; got != want {
+		t.Fatalf("Get().Time = %v, want %v (raised to EnforcementPolicy.MinTime)", got, want)
+	}
+}
+
+// TestServerStoreEnforcementForReflectsLiveUpdate flips the server's
+// EnforcementPolicy mid-connection and checks that EnforcementFor, which a
+// per-connection MethodTracker calls on every ping-strike decision,
+// immediately resolves against the new policy.
+func TestServerStoreEnforcementForReflectsLiveUpdate(t *testing.T) {
+	s := NewServerStore(ServerParameters{}, EnforcementPolicy{MinTime: 5 * time.Minute})
+
+	if got, want := s.EnforcementFor(nil).MinTime, 5*time.Minute; got != want {
+		t.Fatalf("EnforcementFor(nil).MinTime = %v, want %v", got, want)
+	}
+
+	s.Set(ServerParameters{}, EnforcementPolicy{
+		MinTime: 5 * time.Minute,
+		PerMethod: map[string]EnforcementPolicy{
+			"/foo.Bar/Watch": {MinTime: time.Second, PermitWithoutStream: true},
+		},
+	})
+
+	if got, want := s.EnforcementFor([]string{"/foo.Bar/Watch"}).MinTime, time.Second; got != want {
+		t.Fatalf("EnforcementFor([Watch]).MinTime = %v, want %v", got, want)
+	}
+	if got, want := s.EnforcementFor([]string{"/foo.Bar/Unary"}).MinTime, 5*time.Minute; got != want {
+		t.Fatalf("EnforcementFor([Unary]).MinTime = %v, want %v", got, want)
+	}
+}
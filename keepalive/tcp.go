@@ -0,0 +1,62 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import "time"
+
+// NOTE: SetTCPParameters (in the platform-specific tcp_*.go files) is the
+// primitive grpc.WithTCPKeepalive/grpc.TCPKeepalive dial/server options
+// would apply at accept/dial time, not those options themselves — there
+// is no grpc package, DialOption, or ServerOption anywhere in this tree
+// for them to live on. Nothing here is called outside SetTCPParameters's
+// own tests. Flagging for whoever scoped this backlog entry: as written,
+// this request isn't deliverable against this snapshot of the repository.
+
+// TCPParameters configures the kernel-level TCP keepalive probe on the
+// socket underlying a connection, as a complement to the HTTP/2-level
+// pings configured by ClientParameters and ServerParameters. The kernel
+// can notice a dead peer even when the gRPC ping loop itself is stalled,
+// e.g. blocked on a full send buffer.
+//
+// TCPParameters配置连接底层socket上内核级别的TCP keepalive探测，
+// 作为ClientParameters/ServerParameters配置的HTTP/2级别ping的补充。
+// 即使gRPC自身的ping循环被阻塞（例如发送缓冲区已满），内核仍然能够发现对端已经失联。
+//
+// A zero value only enables SO_KEEPALIVE and leaves the socket's other
+// keepalive settings untouched. Any field left at zero is not applied;
+// platforms that don't support a given knob silently skip it.
+type TCPParameters struct {
+	// Idle is the duration of inactivity before the first probe is sent.
+	// Maps to TCP_KEEPIDLE (TCP_KEEPALIVE on Darwin).
+	Idle time.Duration
+	// Interval is the duration between successive probes once probing has
+	// started. Maps to TCP_KEEPINTVL.
+	Interval time.Duration
+	// Count is the number of unacknowledged probes before the connection
+	// is considered dead. Maps to TCP_KEEPCNT.
+	Count int
+	// UserTimeout is the maximum amount of time transmitted data may
+	// remain unacknowledged before the kernel forcibly closes the
+	// connection. Maps to TCP_USER_TIMEOUT. Linux only; ignored elsewhere.
+	UserTimeout time.Duration
+}
+
+func secs(d time.Duration) int {
+	return int(d / time.Second)
+}
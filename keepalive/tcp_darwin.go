@@ -0,0 +1,55 @@
+//go:build darwin
+
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"net"
+	"syscall"
+)
+
+// SetTCPParameters enables SO_KEEPALIVE on conn's underlying socket and
+// applies the non-zero fields of p. Darwin has no TCP_KEEPINTVL/TCP_KEEPCNT
+// equivalent exposed the same way as Linux and no TCP_USER_TIMEOUT, so
+// Interval, Count, and UserTimeout are silently ignored; only Idle (mapped
+// to TCP_KEEPALIVE) is applied.
+//
+// SetTCPParameters在conn底层的socket上启用SO_KEEPALIVE，并应用p中非零的字段。
+// Darwin没有像Linux那样暴露的TCP_KEEPINTVL/TCP_KEEPCNT等价项，也没有TCP_USER_TIMEOUT，
+// 因此Interval、Count、UserTimeout会被静默忽略，只应用Idle（映射到TCP_KEEPALIVE）。
+func SetTCPParameters(conn *net.TCPConn, p TCPParameters) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	cerr := raw.Control(func(fd uintptr) {
+		if serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1); serr != nil {
+			return
+		}
+		if p.Idle > 0 {
+			serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPALIVE, secs(p.Idle))
+		}
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return serr
+}
@@ -0,0 +1,71 @@
+//go:build linux
+
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"net"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetTCPParameters enables SO_KEEPALIVE on conn's underlying socket and
+// applies the non-zero fields of p (TCP_KEEPIDLE, TCP_KEEPINTVL,
+// TCP_KEEPCNT, TCP_USER_TIMEOUT). It is a no-op for any zero-valued field.
+//
+// SetTCPParameters在conn底层的socket上启用SO_KEEPALIVE，
+// 并应用p中非零的字段（TCP_KEEPIDLE、TCP_KEEPINTVL、TCP_KEEPCNT、TCP_USER_TIMEOUT）。
+// 任何取零值的字段都不会被设置。
+func SetTCPParameters(conn *net.TCPConn, p TCPParameters) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	cerr := raw.Control(func(fd uintptr) {
+		if serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1); serr != nil {
+			return
+		}
+		if p.Idle > 0 {
+			if serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, secs(p.Idle)); serr != nil {
+				return
+			}
+		}
+		if p.Interval > 0 {
+			if serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, secs(p.Interval)); serr != nil {
+				return
+			}
+		}
+		if p.Count > 0 {
+			if serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPCNT, p.Count); serr != nil {
+				return
+			}
+		}
+		if p.UserTimeout > 0 {
+			serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, unix.TCP_USER_TIMEOUT, int(p.UserTimeout/time.Millisecond))
+		}
+	})
+	if cerr != nil {
+		return cerr
+	}
+	return serr
+}
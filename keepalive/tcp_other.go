@@ -0,0 +1,35 @@
+//go:build !linux && !darwin && !windows
+
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import "net"
+
+// SetTCPParameters enables keepalive on conn via the portable net API. No
+// fields of p beyond enabling keepalive itself can be applied on this
+// platform, so Idle, Interval, Count, and UserTimeout are silently
+// ignored.
+//
+// SetTCPParameters通过可移植的net API在conn上启用keepalive。
+// 除了启用keepalive本身之外，p的其他字段在该平台上都无法应用，
+// 因此Idle、Interval、Count、UserTimeout都会被静默忽略。
+func SetTCPParameters(conn *net.TCPConn, p TCPParameters) error {
+	return conn.SetKeepAlive(true)
+}
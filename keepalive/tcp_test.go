@@ -0,0 +1,100 @@
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// TestSetTCPParameters exercises SetTCPParameters against a real loopback
+// TCP connection, the same kind of *net.TCPConn a dial/accept path would
+// hand it.
+func TestSetTCPParameters(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			accepted <- err
+			return
+		}
+		defer conn.Close()
+		accepted <- nil
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial: %v", err)
+	}
+	defer conn.Close()
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if !ok {
+		t.Fatalf("conn is %T, want *net.TCPConn", conn)
+	}
+
+	if err := SetTCPParameters(tcpConn, TCPParameters{
+		Idle:        30 * time.Second,
+		Interval:    10 * time.Second,
+		Count:       3,
+		UserTimeout: 45 * time.Second,
+	}); err != nil {
+		t.Fatalf("SetTCPParameters: %v", err)
+	}
+
+	if err := <-accepted; err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+}
+
+// TestSetTCPParametersZeroValueOnlyEnablesKeepalive checks that a zero
+// TCPParameters still enables SO_KEEPALIVE without error; individual
+// knobs are only applied when set.
+func TestSetTCPParametersZeroValueOnlyEnablesKeepalive(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial: %v", err)
+	}
+	defer conn.Close()
+
+	tcpConn := conn.(*net.TCPConn)
+	if err := SetTCPParameters(tcpConn, TCPParameters{}); err != nil {
+		t.Fatalf("SetTCPParameters(zero value): %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+/*
+ *
+ * Copyright 2017 gRPC authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package keepalive
+
+import "net"
+
+// SetTCPParameters enables keepalive on conn via SetKeepAlive and applies
+// Idle via SetKeepAlivePeriod. Interval, Count, and UserTimeout are not
+// applied on Windows and are silently ignored.
+//
+// SetTCPParameters通过SetKeepAlive在conn上启用keepalive，并通过SetKeepAlivePeriod
+// 应用Idle。在Windows上Interval、Count和UserTimeout不会被应用，会被静默忽略。
+func SetTCPParameters(conn *net.TCPConn, p TCPParameters) error {
+	if err := conn.SetKeepAlive(true); err != nil {
+		return err
+	}
+	if p.Idle > 0 {
+		if err := conn.SetKeepAlivePeriod(p.Idle); err != nil {
+			return err
+		}
+	}
+	return nil
+}